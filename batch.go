@@ -0,0 +1,142 @@
+package ssmenv
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// maxConcurrency bounds how many GetParameters calls are in flight at once when a
+// request has been split into multiple batches.
+const maxConcurrency = 5
+
+// Fetch resolves names, each in the same syntax accepted by ReplacedEnv's "ssm://"
+// entries (including ":version" and "@label" qualifiers), to their values using the
+// same batching, concurrency and error handling as ReplacedEnv. It is exported for
+// packages such as ssmenv/koanfprovider that need direct access to the fetch
+// pipeline without going through an environment variable list.
+func Fetch(ctx context.Context, cli ssmClient, names []string, opts ...Option) (map[string]string, error) {
+	return resolveNames(ctx, cli, names, opts...)
+}
+
+// batchFetch resolves keys to their SSM parameter values. Since GetParameters rejects
+// more than defaultBatchSize names per call, keys is split into chunks that are
+// fetched concurrently through a bounded worker pool, retrying transient failures per
+// WithRetry. InvalidParameters are aggregated across all chunks into a single
+// InvalidParametersError. On the first non-InvalidParameters error, outstanding
+// requests are canceled and the error is wrapped in GetParametersError.
+func batchFetch(ctx context.Context, cli ssmClient, keys []string, opts ...Option) (map[string]string, error) {
+	o := newOptions(opts)
+	chunks := chunkStrings(keys, o.batchSize)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type chunkResult struct {
+		params  map[string]string
+		invalid []string
+		err     error
+	}
+
+	jobs := make(chan []string, len(chunks))
+	for _, c := range chunks {
+		jobs <- c
+	}
+	close(jobs)
+
+	results := make(chan chunkResult, len(chunks))
+
+	workers := maxConcurrency
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				params, invalid, err := retryGetParameters(ctx, cli, chunk, o)
+				results <- chunkResult{params: params, invalid: invalid, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	merged := make(map[string]string)
+	var invalidParams []string
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+				cancel()
+			}
+			continue
+		}
+		if len(r.invalid) > 0 {
+			invalidParams = append(invalidParams, r.invalid...)
+			continue
+		}
+		for k, v := range r.params {
+			merged[k] = v
+		}
+	}
+
+	if firstErr != nil {
+		return nil, GetParametersError{Cause: firstErr}
+	}
+	if len(invalidParams) > 0 {
+		return nil, InvalidParametersError{InvalidParameters: invalidParams}
+	}
+
+	return merged, nil
+}
+
+// getParameters issues a single GetParameters call for at most defaultBatchSize
+// names, returning either the resolved parameters, the names the API reported as
+// invalid, or an error.
+func getParameters(ctx context.Context, cli ssmClient, keys []string) (map[string]string, []string, error) {
+	input := ssm.GetParametersInput{
+		Names:          keys,
+		WithDecryption: aws.Bool(true),
+	}
+	res, err := cli.GetParameters(ctx, &input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res.InvalidParameters) > 0 {
+		return nil, res.InvalidParameters, nil
+	}
+
+	ret := make(map[string]string)
+	for _, p := range res.Parameters {
+		if p.Name == nil || p.Value == nil {
+			return nil, nil, NullParameterError{}
+		}
+		ret[*p.Name] = *p.Value
+	}
+	return ret, nil, nil
+}
+
+// chunkStrings splits s into slices of at most size elements each.
+func chunkStrings(s []string, size int) [][]string {
+	if len(s) == 0 {
+		return nil
+	}
+	var chunks [][]string
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[0:size:size])
+	}
+	return append(chunks, s)
+}
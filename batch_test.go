@@ -0,0 +1,169 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeSSMClient implements ssmClient with overridable per-method behavior, so tests
+// don't need a real AWS connection.
+type fakeSSMClient struct {
+	getParameters       func(ctx context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error)
+	getParametersByPath func(ctx context.Context, in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error)
+	getParameter        func(ctx context.Context, in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+}
+
+func (f *fakeSSMClient) GetParameters(ctx context.Context, in *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	return f.getParameters(ctx, in)
+}
+
+func (f *fakeSSMClient) GetParametersByPath(ctx context.Context, in *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	return f.getParametersByPath(ctx, in)
+}
+
+func (f *fakeSSMClient) GetParameter(ctx context.Context, in *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	return f.getParameter(ctx, in)
+}
+
+func TestChunkStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		size int
+		want [][]string
+	}{
+		{"empty", nil, 10, nil},
+		{"exact multiple", []string{"a", "b"}, 2, [][]string{{"a", "b"}}},
+		{"remainder", []string{"a", "b", "c"}, 2, [][]string{{"a", "b"}, {"c"}}},
+		{"size larger than input", []string{"a"}, 10, [][]string{{"a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkStrings(tt.in, tt.size)
+			if len(got) != len(tt.want) {
+				t.Fatalf("chunkStrings(%v, %d) = %v, want %v", tt.in, tt.size, got, tt.want)
+			}
+			for i := range got {
+				if strings.Join(got[i], ",") != strings.Join(tt.want[i], ",") {
+					t.Fatalf("chunkStrings(%v, %d) = %v, want %v", tt.in, tt.size, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchFetchSplitsAcrossBatchLimit(t *testing.T) {
+	keys := make([]string, 25)
+	want := make(map[string]string, 25)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+		want[keys[i]] = fmt.Sprintf("v%d", i)
+	}
+
+	var mu sync.Mutex
+	var callSizes []int
+
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			if len(in.Names) > defaultBatchSize {
+				t.Errorf("GetParameters called with %d names, want <= %d", len(in.Names), defaultBatchSize)
+			}
+
+			mu.Lock()
+			callSizes = append(callSizes, len(in.Names))
+			mu.Unlock()
+
+			var ps []ssmtypes.Parameter
+			for _, n := range in.Names {
+				ps = append(ps, ssmtypes.Parameter{Name: aws.String(n), Value: aws.String(want[n])})
+			}
+			return &ssm.GetParametersOutput{Parameters: ps}, nil
+		},
+	}
+
+	got, err := batchFetch(context.Background(), cli, keys)
+	if err != nil {
+		t.Fatalf("batchFetch returned error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d parameters, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("got[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	if len(callSizes) != 3 { // 25 keys / 10 per batch = 3 calls
+		t.Errorf("got %d GetParameters calls, want 3", len(callSizes))
+	}
+}
+
+func TestBatchFetchAggregatesInvalidParametersAcrossChunks(t *testing.T) {
+	keys := []string{"a", "b", "bad1", "c", "d", "e", "f", "g", "h", "i", "bad2", "j"}
+
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			var invalid []string
+			var ps []ssmtypes.Parameter
+			for _, n := range in.Names {
+				if strings.HasPrefix(n, "bad") {
+					invalid = append(invalid, n)
+					continue
+				}
+				ps = append(ps, ssmtypes.Parameter{Name: aws.String(n), Value: aws.String(n)})
+			}
+			return &ssm.GetParametersOutput{Parameters: ps, InvalidParameters: invalid}, nil
+		},
+	}
+
+	_, err := batchFetch(context.Background(), cli, keys, WithBatchSize(5))
+
+	var invalidErr InvalidParametersError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("got error %v, want InvalidParametersError", err)
+	}
+
+	sort.Strings(invalidErr.InvalidParameters)
+	want := []string{"bad1", "bad2"}
+	if strings.Join(invalidErr.InvalidParameters, ",") != strings.Join(want, ",") {
+		t.Fatalf("got invalid parameters %v, want %v", invalidErr.InvalidParameters, want)
+	}
+}
+
+func TestBatchFetchCancelsOutstandingRequestsOnFirstError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	cli := &fakeSSMClient{
+		getParameters: func(ctx context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			if in.Names[0] == "bad" {
+				return nil, wantErr
+			}
+			// Every other chunk blocks until batchFetch cancels the shared context
+			// after seeing the first error, proving it doesn't wait for them.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	keys := []string{"bad", "ok1", "ok2", "ok3", "ok4", "ok5", "ok6", "ok7", "ok8", "ok9", "ok10"}
+	_, err := batchFetch(context.Background(), cli, keys, WithBatchSize(1))
+
+	var getErr GetParametersError
+	if !errors.As(err, &getErr) {
+		t.Fatalf("got error %v, want GetParametersError", err)
+	}
+	if !errors.Is(getErr.Cause, wantErr) {
+		t.Fatalf("got cause %v, want %v", getErr.Cause, wantErr)
+	}
+}
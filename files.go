@@ -0,0 +1,103 @@
+package ssmenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileTarget describes an SSM parameter that should be materialized as a file on
+// disk, for secrets such as TLS keys or kubeconfigs that must exist as files rather
+// than environment variables.
+type FileTarget struct {
+	// Name is the SSM parameter name to fetch.
+	Name string
+	// Path is the destination file path.
+	Path string
+	// Mode is the file permission bits to set on Path. It is required: the zero
+	// value (0000) would leave the written secret unreadable.
+	Mode os.FileMode
+	// Uid and Gid chown Path when both are non-nil. Leaving either nil (the zero
+	// value) leaves ownership unchanged, so a FileTarget built without setting them
+	// never rewrites ownership.
+	Uid *int
+	Gid *int
+}
+
+// FileWriteError is returned when writing a FileTarget to disk fails.
+type FileWriteError struct {
+	Path string
+	// Cause contains the original error.
+	Cause error
+}
+
+func (e FileWriteError) Error() string {
+	return fmt.Sprintf("failed to write file %s: %v", e.Path, e.Cause)
+}
+
+func (e FileWriteError) Unwrap() error {
+	return e.Cause
+}
+
+// ReplaceEnvAndFiles behaves like ReplacedEnv, additionally materializing each
+// FileTarget as a file on disk. The env-var and file targets share a single SSM
+// GetParameters round-trip.
+func ReplaceEnvAndFiles(ctx context.Context, cli ssmClient, envs []string, files []FileTarget, opts ...Option) (map[string]string, error) {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+
+	orig, ps, err := resolveEnv(ctx, cli, envs, names, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		val, ok := ps[f.Name]
+		if !ok {
+			return nil, ParameterNotFoundError{Key: f.Name}
+		}
+		if err := writeFile(f, val); err != nil {
+			return nil, err
+		}
+	}
+
+	return orig, nil
+}
+
+// writeFile atomically writes value to f.Path: it writes a temp file in the same
+// directory, applies f.Mode and, when both are set, f.Uid/f.Gid to it, then renames
+// it onto f.Path.
+func writeFile(f FileTarget, value string) error {
+	dir := filepath.Dir(f.Path)
+	tmp, err := os.CreateTemp(dir, ".ssmenv-*")
+	if err != nil {
+		return FileWriteError{Path: f.Path, Cause: err}
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(value); err != nil {
+		tmp.Close()
+		return FileWriteError{Path: f.Path, Cause: err}
+	}
+	if err := tmp.Close(); err != nil {
+		return FileWriteError{Path: f.Path, Cause: err}
+	}
+
+	if err := os.Chmod(tmp.Name(), f.Mode); err != nil {
+		return FileWriteError{Path: f.Path, Cause: err}
+	}
+	if f.Uid != nil && f.Gid != nil {
+		if err := os.Chown(tmp.Name(), *f.Uid, *f.Gid); err != nil {
+			return FileWriteError{Path: f.Path, Cause: err}
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return FileWriteError{Path: f.Path, Cause: err}
+	}
+
+	return nil
+}
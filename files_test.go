@@ -0,0 +1,164 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestWriteFileWritesContentAndExactModeRegardlessOfUmask(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+
+	f := FileTarget{Name: "/app/secret", Path: path, Mode: 0o640}
+	if err := writeFile(f, "s3cr3t"); err != nil {
+		t.Fatalf("writeFile returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) returned error: %v", path, err)
+	}
+	if string(got) != "s3cr3t" {
+		t.Errorf("content = %q, want %q", got, "s3cr3t")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat(%q) returned error: %v", path, err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want %v (chmod must not be masked by umask)", info.Mode().Perm(), os.FileMode(0o640))
+	}
+}
+
+func TestWriteFileLeavesTargetUntouchedWhenRenameFails(t *testing.T) {
+	dir := t.TempDir()
+	// Path points at a directory, not a regular file: the final os.Rename of a
+	// regular temp file onto a directory fails, so this exercises a mid-write
+	// error after the temp file was written and chmod'd but before rename.
+	path := filepath.Join(dir, "secret")
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatalf("Mkdir(%q) returned error: %v", path, err)
+	}
+
+	f := FileTarget{Name: "/app/secret", Path: path, Mode: 0o640}
+	err := writeFile(f, "s3cr3t")
+	if err == nil {
+		t.Fatal("writeFile returned nil error, want a rename error")
+	}
+
+	var writeErr FileWriteError
+	if !errors.As(err, &writeErr) {
+		t.Fatalf("got error %v, want FileWriteError", err)
+	}
+	if writeErr.Path != path {
+		t.Errorf("Path = %q, want %q", writeErr.Path, path)
+	}
+	if !errors.Is(err, writeErr.Cause) {
+		t.Errorf("Unwrap() = %v, want the underlying rename error", writeErr.Cause)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat(%q) returned error: %v", path, err)
+	}
+	if !info.IsDir() {
+		t.Error("target was replaced despite the rename failing; writeFile is not atomic")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir(%q) returned error: %v", dir, err)
+	}
+	for _, e := range entries {
+		if e.Name() != "secret" {
+			t.Errorf("leftover temp file %q was not cleaned up", e.Name())
+		}
+	}
+}
+
+func TestWriteFileWrapsIOFailureIntoFileWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "no-such-subdir", "secret")
+
+	f := FileTarget{Name: "/app/secret", Path: path, Mode: 0o640}
+	err := writeFile(f, "s3cr3t")
+
+	var writeErr FileWriteError
+	if !errors.As(err, &writeErr) {
+		t.Fatalf("got error %v, want FileWriteError", err)
+	}
+	if writeErr.Path != path {
+		t.Errorf("Path = %q, want %q", writeErr.Path, path)
+	}
+	if writeErr.Cause == nil {
+		t.Error("Cause is nil, want the underlying CreateTemp error")
+	}
+	if !errors.Is(err, writeErr.Unwrap()) {
+		t.Error("Unwrap() does not return Cause")
+	}
+}
+
+func TestReplaceEnvAndFilesWritesFileAndReplacesEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cert.pem")
+
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			var ps []ssmtypes.Parameter
+			for _, n := range in.Names {
+				ps = append(ps, ssmtypes.Parameter{Name: aws.String(n), Value: aws.String("value-" + n)})
+			}
+			return &ssm.GetParametersOutput{Parameters: ps}, nil
+		},
+	}
+
+	envs := []string{"HOST=ssm:///app/host"}
+	files := []FileTarget{{Name: "/app/cert", Path: path, Mode: 0o600}}
+
+	orig, err := ReplaceEnvAndFiles(context.Background(), cli, envs, files)
+	if err != nil {
+		t.Fatalf("ReplaceEnvAndFiles returned error: %v", err)
+	}
+	if orig["HOST"] != "value-/app/host" {
+		t.Errorf(`orig["HOST"] = %q, want %q`, orig["HOST"], "value-/app/host")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) returned error: %v", path, err)
+	}
+	if string(got) != "value-/app/cert" {
+		t.Errorf("content = %q, want %q", got, "value-/app/cert")
+	}
+}
+
+func TestReplaceEnvAndFilesReturnsParameterNotFoundForMissingFileTarget(t *testing.T) {
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			// The requested name is never returned, simulating SSM not finding it.
+			return &ssm.GetParametersOutput{}, nil
+		},
+	}
+
+	files := []FileTarget{{Name: "/app/missing", Path: filepath.Join(t.TempDir(), "out"), Mode: 0o600}}
+	_, err := ReplaceEnvAndFiles(context.Background(), cli, nil, files)
+
+	var notFoundErr ParameterNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want ParameterNotFoundError", err)
+	}
+	if notFoundErr.Key != "/app/missing" {
+		t.Errorf("Key = %q, want %q", notFoundErr.Key, "/app/missing")
+	}
+}
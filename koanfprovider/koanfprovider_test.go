@@ -0,0 +1,112 @@
+package koanfprovider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeSSMClient implements sdkClient with overridable per-method behavior, so tests
+// don't need a real AWS connection.
+type fakeSSMClient struct {
+	getParameters       func(ctx context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error)
+	getParametersByPath func(ctx context.Context, in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error)
+}
+
+func (f *fakeSSMClient) GetParameter(_ context.Context, _ *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	panic("not implemented")
+}
+
+func (f *fakeSSMClient) GetParameters(ctx context.Context, in *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	return f.getParameters(ctx, in)
+}
+
+func (f *fakeSSMClient) GetParametersByPath(ctx context.Context, in *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	return f.getParametersByPath(ctx, in)
+}
+
+func TestSetNestedBuildsNestedMapFromSlashSegments(t *testing.T) {
+	out := make(map[string]interface{})
+	setNested(out, []string{"app", "db", "host"}, "db.internal")
+
+	want := map[string]interface{}{
+		"app": map[string]interface{}{
+			"db": map[string]interface{}{
+				"host": "db.internal",
+			},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v, want %#v", out, want)
+	}
+}
+
+func TestReadDispatchesToNamesWhenSet(t *testing.T) {
+	var gotNames []string
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			gotNames = append(gotNames, in.Names...)
+			return &ssm.GetParametersOutput{
+				Parameters: []ssmtypes.Parameter{{Name: aws.String("/app/db/host"), Value: aws.String("db.internal")}},
+			}, nil
+		},
+	}
+
+	p := &Provider{Client: cli, Names: []string{"/app/db/host"}}
+	out, err := p.Read()
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	want := map[string]interface{}{"app": map[string]interface{}{"db": map[string]interface{}{"host": "db.internal"}}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v, want %#v", out, want)
+	}
+	if len(gotNames) != 1 || gotNames[0] != "/app/db/host" {
+		t.Errorf("GetParameters called with %v, want [/app/db/host]", gotNames)
+	}
+}
+
+func TestReadDispatchesToPathWhenSet(t *testing.T) {
+	var gotPath string
+	cli := &fakeSSMClient{
+		getParametersByPath: func(_ context.Context, in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+			gotPath = *in.Path
+			return &ssm.GetParametersByPathOutput{
+				Parameters: []ssmtypes.Parameter{{Name: aws.String("/app/db/host"), Value: aws.String("db.internal")}},
+			}, nil
+		},
+	}
+
+	p := &Provider{Client: cli, Path: "/app"}
+	out, err := p.Read()
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	want := map[string]interface{}{"app": map[string]interface{}{"db": map[string]interface{}{"host": "db.internal"}}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v, want %#v", out, want)
+	}
+	if gotPath != "/app" {
+		t.Errorf("GetParametersByPath called with path %q, want %q", gotPath, "/app")
+	}
+}
+
+func TestReadRequiresExactlyOneOfNamesOrPath(t *testing.T) {
+	p := &Provider{}
+	if _, err := p.Read(); err == nil {
+		t.Fatal("Read returned nil error, want an error when neither Names nor Path is set")
+	}
+}
+
+func TestReadBytesReturnsUnsupportedError(t *testing.T) {
+	p := &Provider{}
+	if _, err := p.ReadBytes(); err == nil {
+		t.Fatal("ReadBytes returned nil error, want an unsupported error")
+	}
+}
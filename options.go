@@ -0,0 +1,57 @@
+package ssmenv
+
+import "time"
+
+// defaultBatchSize is the maximum number of parameter names SSM's GetParameters
+// accepts in a single call.
+const defaultBatchSize = 10
+
+// options holds the resolved configuration built from a slice of Option values.
+type options struct {
+	batchSize   int
+	pathKeyMode PathKeyMode
+
+	retryMaxAttempts int
+	retryBase        time.Duration
+	retryCap         time.Duration
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		batchSize:        defaultBatchSize,
+		pathKeyMode:      PathKeyPrefixed,
+		retryMaxAttempts: 1,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.batchSize <= 0 {
+		// A non-positive batch size would make chunkStrings loop forever, so fall
+		// back to the default rather than let a bad WithBatchSize value hang.
+		o.batchSize = defaultBatchSize
+	}
+	return o
+}
+
+// Option configures the behavior of ReplacedEnv and related functions.
+type Option func(*options)
+
+// WithBatchSize overrides the number of parameter names sent in a single
+// GetParameters call. The AWS API rejects more than 10 names per call, so this
+// should only be lowered (e.g. in tests); raising it will cause requests to fail.
+func WithBatchSize(n int) Option {
+	return func(o *options) { o.batchSize = n }
+}
+
+// WithRetry retries a GetParameters call up to maxAttempts times (including the
+// first attempt) when SSM reports throttling or a server-side error, sleeping
+// between attempts with exponential backoff and full jitter: a random duration
+// between 0 and min(cap, base*2^attempt). The default, when WithRetry is not given,
+// is a single attempt with no retries.
+func WithRetry(maxAttempts int, base, cap time.Duration) Option {
+	return func(o *options) {
+		o.retryMaxAttempts = maxAttempts
+		o.retryBase = base
+		o.retryCap = cap
+	}
+}
@@ -0,0 +1,124 @@
+package ssmenv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ssmPathPrefix marks an environment variable value that should expand into many
+// environment variables, one per parameter found recursively under the given path.
+const ssmPathPrefix = "ssm-path://"
+
+// PathKeyMode controls how the names of environment variables produced from a
+// "ssm-path://" entry are derived from the parameter path.
+type PathKeyMode int
+
+const (
+	// PathKeyPrefixed appends the trailing segment of the parameter name, uppercased,
+	// to the original KEY. This is the default: for KEY="DB_" and parameter
+	// "/prod/db/HOST", the resulting variable is "DB_HOST".
+	PathKeyPrefixed PathKeyMode = iota
+	// PathKeySuffixOnly drops the KEY and uses only the trailing segment, uppercased.
+	PathKeySuffixOnly
+	// PathKeyEntirePath uses the full parameter path below the queried prefix,
+	// uppercased with "/" replaced by "_", mirroring ssmwrap's -env-entire-path.
+	PathKeyEntirePath
+)
+
+// WithPathKeyMode overrides how environment variable names are derived from
+// "ssm-path://" entries. The default is PathKeyPrefixed.
+func WithPathKeyMode(m PathKeyMode) Option {
+	return func(o *options) { o.pathKeyMode = m }
+}
+
+// PathNotFoundError is returned when a "ssm-path://" entry has no parameters under it.
+type PathNotFoundError struct {
+	Path string
+}
+
+func (e PathNotFoundError) Error() string {
+	return fmt.Sprintf("no SSM parameters found under path: %s", e.Path)
+}
+
+// FetchByPath returns every SSM parameter found recursively under path, keyed by
+// full parameter name. It is exported for packages such as ssmenv/koanfprovider that
+// need the raw parameter tree rather than an expanded environment variable map.
+func FetchByPath(ctx context.Context, cli ssmClient, path string, opts ...Option) (map[string]string, error) {
+	return fetchByPath(ctx, cli, path, opts...)
+}
+
+// fetchByPath returns every parameter found recursively under path, paging through
+// NextToken as needed and retrying each page per WithRetry.
+func fetchByPath(ctx context.Context, cli ssmClient, path string, opts ...Option) (map[string]string, error) {
+	o := newOptions(opts)
+
+	ret := make(map[string]string)
+	var nextToken *string
+
+	for {
+		page, next, err := retryGetParametersByPath(ctx, cli, path, nextToken, o)
+		if err != nil {
+			return nil, GetParametersError{Cause: err}
+		}
+
+		for k, v := range page {
+			ret[k] = v
+		}
+
+		if next == nil {
+			break
+		}
+		nextToken = next
+	}
+
+	if len(ret) == 0 {
+		return nil, PathNotFoundError{Path: path}
+	}
+
+	return ret, nil
+}
+
+// getParametersByPath issues a single GetParametersByPath call, returning the
+// parameters found on this page and the NextToken for the next one, if any.
+func getParametersByPath(ctx context.Context, cli ssmClient, path string, nextToken *string) (map[string]string, *string, error) {
+	input := ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+		NextToken:      nextToken,
+	}
+	res, err := cli.GetParametersByPath(ctx, &input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ret := make(map[string]string)
+	for _, p := range res.Parameters {
+		if p.Name == nil || p.Value == nil {
+			return nil, nil, NullParameterError{}
+		}
+		ret[*p.Name] = *p.Value
+	}
+	return ret, res.NextToken, nil
+}
+
+// envKeyForPathParam derives the environment variable name for a parameter found
+// under a "ssm-path://" path, given the original KEY and the query path.
+func envKeyForPathParam(mode PathKeyMode, key, path, name string) string {
+	trimmed := strings.TrimPrefix(name, path)
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	switch mode {
+	case PathKeySuffixOnly:
+		return strings.ToUpper(trimmed)
+	case PathKeyEntirePath:
+		return strings.ToUpper(strings.ReplaceAll(trimmed, "/", "_"))
+	default:
+		segments := strings.Split(trimmed, "/")
+		return key + strings.ToUpper(segments[len(segments)-1])
+	}
+}
@@ -0,0 +1,82 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestEnvKeyForPathParam(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  PathKeyMode
+		key   string
+		path  string
+		pname string
+		want  string
+	}{
+		{"prefixed", PathKeyPrefixed, "DB_", "/prod/db", "/prod/db/host", "DB_HOST"},
+		{"suffix only", PathKeySuffixOnly, "DB_", "/prod/db", "/prod/db/host", "HOST"},
+		{"entire path", PathKeyEntirePath, "DB_", "/prod/db", "/prod/db/host/read", "HOST_READ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envKeyForPathParam(tt.mode, tt.key, tt.path, tt.pname); got != tt.want {
+				t.Errorf("envKeyForPathParam(%v, %q, %q, %q) = %q, want %q", tt.mode, tt.key, tt.path, tt.pname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchByPathPagesThroughNextToken(t *testing.T) {
+	var gotTokens []*string
+
+	cli := &fakeSSMClient{
+		getParametersByPath: func(_ context.Context, in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+			gotTokens = append(gotTokens, in.NextToken)
+			if in.NextToken == nil {
+				return &ssm.GetParametersByPathOutput{
+					Parameters: []ssmtypes.Parameter{{Name: aws.String("/app/a"), Value: aws.String("1")}},
+					NextToken:  aws.String("page2"),
+				}, nil
+			}
+			return &ssm.GetParametersByPathOutput{
+				Parameters: []ssmtypes.Parameter{{Name: aws.String("/app/b"), Value: aws.String("2")}},
+			}, nil
+		},
+	}
+
+	got, err := fetchByPath(context.Background(), cli, "/app")
+	if err != nil {
+		t.Fatalf("fetchByPath returned error: %v", err)
+	}
+	if got["/app/a"] != "1" || got["/app/b"] != "2" {
+		t.Fatalf("got %v, want both /app/a=1 and /app/b=2", got)
+	}
+	if len(gotTokens) != 2 || gotTokens[0] != nil || gotTokens[1] == nil || *gotTokens[1] != "page2" {
+		t.Fatalf("got tokens %v, want [nil, page2]", gotTokens)
+	}
+}
+
+func TestFetchByPathReturnsPathNotFoundWhenEmpty(t *testing.T) {
+	cli := &fakeSSMClient{
+		getParametersByPath: func(_ context.Context, _ *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+			return &ssm.GetParametersByPathOutput{}, nil
+		},
+	}
+
+	_, err := fetchByPath(context.Background(), cli, "/app/missing")
+
+	var notFoundErr PathNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want PathNotFoundError", err)
+	}
+	if notFoundErr.Path != "/app/missing" {
+		t.Errorf("Path = %q, want %q", notFoundErr.Path, "/app/missing")
+	}
+}
@@ -0,0 +1,204 @@
+package ssmenv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// parsedRef is a parsed "ssm://" reference, optionally pinned to a specific version
+// or label, matching AWS's conventions for GetParameter (e.g. "ssm://name:5" or
+// "ssm://name@production").
+type parsedRef struct {
+	name    string
+	version string
+	label   string
+}
+
+// MalformedReferenceError is returned when a "ssm://" reference cannot be parsed, for
+// example when it specifies both a version and a label.
+type MalformedReferenceError struct {
+	Reference string
+}
+
+func (e MalformedReferenceError) Error() string {
+	return fmt.Sprintf("malformed SSM parameter reference: %s", e.Reference)
+}
+
+// parseRef parses ref, the part of a "ssm://" entry left after the prefix is
+// stripped.
+func parseRef(ref string) (parsedRef, error) {
+	hasVersion := strings.Contains(ref, ":")
+	hasLabel := strings.Contains(ref, "@")
+
+	switch {
+	case hasVersion && hasLabel:
+		return parsedRef{}, MalformedReferenceError{Reference: ref}
+	case hasVersion:
+		name, version, _ := strings.Cut(ref, ":")
+		return parsedRef{name: name, version: version}, nil
+	case hasLabel:
+		name, label, _ := strings.Cut(ref, "@")
+		return parsedRef{name: name, label: label}, nil
+	default:
+		return parsedRef{name: ref}, nil
+	}
+}
+
+// qualified reports whether the reference pins a version or label, which
+// GetParameters cannot resolve and must instead be fetched one at a time via
+// GetParameter.
+func (r parsedRef) qualified() bool {
+	return r.version != "" || r.label != ""
+}
+
+// qualifiedName returns the value to pass as GetParameter's Name parameter, which
+// accepts both "name:version" and "name:label".
+func (r parsedRef) qualifiedName() string {
+	switch {
+	case r.version != "":
+		return r.name + ":" + r.version
+	case r.label != "":
+		return r.name + ":" + r.label
+	default:
+		return r.name
+	}
+}
+
+// qualifiedRef pairs a parsed reference with the original "ssm://"-stripped string it
+// came from, so results can be keyed the same way as unqualified names.
+type qualifiedRef struct {
+	raw string
+	ref parsedRef
+}
+
+// resolveNames resolves keys, a list of "ssm://"-stripped references, to their
+// values. Unqualified names are resolved together through the batched GetParameters
+// pipeline; names pinned to a version or label are resolved individually through
+// GetParameter, bounded by the same concurrency limit as batchFetch. The returned
+// map is keyed by the original strings in keys.
+func resolveNames(ctx context.Context, cli ssmClient, keys []string, opts ...Option) (map[string]string, error) {
+	var unqualified []string
+	var qualified []qualifiedRef
+
+	for _, k := range keys {
+		ref, err := parseRef(k)
+		if err != nil {
+			return nil, err
+		}
+		if ref.qualified() {
+			qualified = append(qualified, qualifiedRef{raw: k, ref: ref})
+		} else {
+			unqualified = append(unqualified, k)
+		}
+	}
+
+	merged := make(map[string]string)
+
+	if len(unqualified) > 0 {
+		ps, err := batchFetch(ctx, cli, unqualified, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range ps {
+			merged[k] = v
+		}
+	}
+
+	if len(qualified) > 0 {
+		ps, err := fetchQualified(ctx, cli, qualified)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range ps {
+			merged[k] = v
+		}
+	}
+
+	return merged, nil
+}
+
+// fetchQualified resolves version- or label-pinned references one at a time via
+// GetParameter, bounded by maxConcurrency and canceling outstanding requests on the
+// first error.
+func fetchQualified(ctx context.Context, cli ssmClient, refs []qualifiedRef) (map[string]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		raw   string
+		value string
+		err   error
+	}
+
+	jobs := make(chan qualifiedRef, len(refs))
+	for _, r := range refs {
+		jobs <- r
+	}
+	close(jobs)
+
+	results := make(chan result, len(refs))
+
+	workers := maxConcurrency
+	if workers > len(refs) {
+		workers = len(refs)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				value, err := getParameter(ctx, cli, r.ref)
+				results <- result{raw: r.raw, value: value, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	merged := make(map[string]string)
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+				cancel()
+			}
+			continue
+		}
+		merged[r.raw] = r.value
+	}
+
+	if firstErr != nil {
+		return nil, GetParametersError{Cause: firstErr}
+	}
+
+	return merged, nil
+}
+
+func getParameter(ctx context.Context, cli ssmClient, ref parsedRef) (string, error) {
+	input := ssm.GetParameterInput{
+		Name:           aws.String(ref.qualifiedName()),
+		WithDecryption: aws.Bool(true),
+	}
+	res, err := cli.GetParameter(ctx, &input)
+	if err != nil {
+		return "", err
+	}
+	if res.Parameter == nil || res.Parameter.Name == nil || res.Parameter.Value == nil {
+		return "", NullParameterError{}
+	}
+	return *res.Parameter.Value, nil
+}
@@ -0,0 +1,148 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		want parsedRef
+	}{
+		{"plain name", "/app/host", parsedRef{name: "/app/host"}},
+		{"version", "/app/host:5", parsedRef{name: "/app/host", version: "5"}},
+		{"label", "/app/host@production", parsedRef{name: "/app/host", label: "production"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRef(tt.ref)
+			if err != nil {
+				t.Fatalf("parseRef(%q) returned error: %v", tt.ref, err)
+			}
+			if got != tt.want {
+				t.Fatalf("parseRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefMalformedWithBothVersionAndLabel(t *testing.T) {
+	_, err := parseRef("/app/host:5@production")
+
+	var malformedErr MalformedReferenceError
+	if !errors.As(err, &malformedErr) {
+		t.Fatalf("got error %v, want MalformedReferenceError", err)
+	}
+	if malformedErr.Reference != "/app/host:5@production" {
+		t.Errorf("Reference = %q, want %q", malformedErr.Reference, "/app/host:5@production")
+	}
+}
+
+func TestParsedRefQualified(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  parsedRef
+		want bool
+	}{
+		{"plain", parsedRef{name: "n"}, false},
+		{"version", parsedRef{name: "n", version: "1"}, true},
+		{"label", parsedRef{name: "n", label: "prod"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.qualified(); got != tt.want {
+				t.Errorf("qualified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsedRefQualifiedName(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  parsedRef
+		want string
+	}{
+		{"plain", parsedRef{name: "n"}, "n"},
+		{"version", parsedRef{name: "n", version: "5"}, "n:5"},
+		{"label", parsedRef{name: "n", label: "production"}, "n:production"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.qualifiedName(); got != tt.want {
+				t.Errorf("qualifiedName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNamesSplitsQualifiedAndUnqualified(t *testing.T) {
+	var gotBatchNames []string
+	var gotGetParameterNames []string
+
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			gotBatchNames = append(gotBatchNames, in.Names...)
+			var ps []ssmtypes.Parameter
+			for _, n := range in.Names {
+				ps = append(ps, ssmtypes.Parameter{Name: aws.String(n), Value: aws.String("plain-" + n)})
+			}
+			return &ssm.GetParametersOutput{Parameters: ps}, nil
+		},
+		getParameter: func(_ context.Context, in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+			gotGetParameterNames = append(gotGetParameterNames, *in.Name)
+			return &ssm.GetParameterOutput{
+				Parameter: &ssmtypes.Parameter{Name: in.Name, Value: aws.String("pinned-" + *in.Name)},
+			}, nil
+		},
+	}
+
+	got, err := resolveNames(context.Background(), cli, []string{"/app/host", "/app/cert:5", "/app/key@production"})
+	if err != nil {
+		t.Fatalf("resolveNames returned error: %v", err)
+	}
+
+	if got["/app/host"] != "plain-/app/host" {
+		t.Errorf(`got["/app/host"] = %q, want "plain-/app/host"`, got["/app/host"])
+	}
+	if got["/app/cert:5"] != "pinned-/app/cert:5" {
+		t.Errorf(`got["/app/cert:5"] = %q, want "pinned-/app/cert:5"`, got["/app/cert:5"])
+	}
+	if got["/app/key@production"] != "pinned-/app/key:production" {
+		t.Errorf(`got["/app/key@production"] = %q, want "pinned-/app/key:production"`, got["/app/key@production"])
+	}
+
+	if len(gotBatchNames) != 1 || gotBatchNames[0] != "/app/host" {
+		t.Errorf("GetParameters called with %v, want [/app/host]", gotBatchNames)
+	}
+	if len(gotGetParameterNames) != 2 {
+		t.Errorf("GetParameter called %d times, want 2: %v", len(gotGetParameterNames), gotGetParameterNames)
+	}
+}
+
+func TestResolveNamesPropagatesGetParameterError(t *testing.T) {
+	wantErr := errors.New("boom")
+	cli := &fakeSSMClient{
+		getParameter: func(_ context.Context, _ *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+			return nil, wantErr
+		},
+	}
+
+	_, err := resolveNames(context.Background(), cli, []string{"/app/host:5"})
+
+	var getErr GetParametersError
+	if !errors.As(err, &getErr) {
+		t.Fatalf("got error %v, want GetParametersError", err)
+	}
+	if !errors.Is(getErr.Cause, wantErr) {
+		t.Fatalf("got cause %v, want %v", getErr.Cause, wantErr)
+	}
+}
@@ -0,0 +1,98 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	smithy "github.com/aws/smithy-go"
+)
+
+// retryGetParameters calls getParameters, retrying up to o.retryMaxAttempts times
+// with exponential-backoff-with-full-jitter sleeps between attempts, for errors that
+// isRetryable considers transient.
+func retryGetParameters(ctx context.Context, cli ssmClient, keys []string, o *options) (map[string]string, []string, error) {
+	var lastErr error
+	for attempt := 0; attempt < o.retryMaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, o.retryBase, o.retryCap, attempt); err != nil {
+				return nil, nil, lastErr
+			}
+		}
+
+		params, invalid, err := getParameters(ctx, cli, keys)
+		if err == nil {
+			return params, invalid, nil
+		}
+		if !isRetryable(err) {
+			return nil, nil, err
+		}
+		lastErr = err
+	}
+	return nil, nil, lastErr
+}
+
+// retryGetParametersByPath calls getParametersByPath, retrying up to
+// o.retryMaxAttempts times with the same backoff policy as retryGetParameters. The
+// returned error, like getParametersByPath's, is unwrapped; callers are responsible
+// for wrapping it (see fetchByPath).
+func retryGetParametersByPath(ctx context.Context, cli ssmClient, path string, nextToken *string, o *options) (map[string]string, *string, error) {
+	var lastErr error
+	for attempt := 0; attempt < o.retryMaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, o.retryBase, o.retryCap, attempt); err != nil {
+				return nil, nil, lastErr
+			}
+		}
+
+		params, next, err := getParametersByPath(ctx, cli, path, nextToken)
+		if err == nil {
+			return params, next, nil
+		}
+		if !isRetryable(err) {
+			return nil, nil, err
+		}
+		lastErr = err
+	}
+	return nil, nil, lastErr
+}
+
+// sleepBackoff sleeps for a full-jitter exponential backoff duration, returning
+// early with ctx.Err() if ctx is canceled first.
+func sleepBackoff(ctx context.Context, base, cap time.Duration, attempt int) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(fullJitterBackoff(base, cap, attempt)):
+		return nil
+	}
+}
+
+// fullJitterBackoff returns a random duration in [0, min(cap, base*2^attempt)).
+func fullJitterBackoff(base, cap time.Duration, attempt int) time.Duration {
+	ceiling := base << attempt
+	if ceiling <= 0 || ceiling > cap {
+		ceiling = cap
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// isRetryable reports whether err is a transient AWS SSM failure worth retrying:
+// throttling or a server-side (5xx) error.
+func isRetryable(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.ErrorCode() {
+	case "ThrottlingException", "ProvisionedThroughputExceededException":
+		return true
+	}
+
+	return apiErr.ErrorFault() == smithy.FaultServer
+}
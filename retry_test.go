@@ -0,0 +1,131 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	smithy "github.com/aws/smithy-go"
+)
+
+func TestFullJitterBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    time.Duration
+		cap     time.Duration
+		attempt int
+		maxWant time.Duration
+	}{
+		{"first attempt bounded by base", 100 * time.Millisecond, time.Second, 0, 100 * time.Millisecond},
+		{"grows exponentially", 100 * time.Millisecond, time.Second, 2, 400 * time.Millisecond},
+		{"clamped to cap", 100 * time.Millisecond, 150 * time.Millisecond, 5, 150 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := fullJitterBackoff(tt.base, tt.cap, tt.attempt)
+				if got < 0 || got >= tt.maxWant {
+					t.Fatalf("fullJitterBackoff(%v, %v, %d) = %v, want in [0, %v)", tt.base, tt.cap, tt.attempt, got, tt.maxWant)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"plain error", errors.New("boom"), false},
+		{"throttling", &smithy.GenericAPIError{Code: "ThrottlingException"}, true},
+		{"provisioned throughput exceeded", &smithy.GenericAPIError{Code: "ProvisionedThroughputExceededException"}, true},
+		{"server fault", &smithy.GenericAPIError{Code: "InternalServerError", Fault: smithy.FaultServer}, true},
+		{"client fault", &smithy.GenericAPIError{Code: "ValidationException", Fault: smithy.FaultClient}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchFetchRetriesThrottlingThenSucceeds(t *testing.T) {
+	attempts := 0
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, &smithy.GenericAPIError{Code: "ThrottlingException"}
+			}
+			return &ssm.GetParametersOutput{
+				Parameters: []ssmtypes.Parameter{{Name: aws.String("k"), Value: aws.String("v")}},
+			}, nil
+		},
+	}
+
+	got, err := batchFetch(context.Background(), cli, []string{"k"}, WithRetry(5, time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("batchFetch returned error: %v", err)
+	}
+	if got["k"] != "v" {
+		t.Errorf(`got["k"] = %q, want "v"`, got["k"])
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestBatchFetchStopsRetryingOnNonRetryableError(t *testing.T) {
+	attempts := 0
+	wantErr := &smithy.GenericAPIError{Code: "ValidationException", Fault: smithy.FaultClient}
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, _ *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			attempts++
+			return nil, wantErr
+		},
+	}
+
+	_, err := batchFetch(context.Background(), cli, []string{"k"}, WithRetry(5, time.Millisecond, 10*time.Millisecond))
+
+	var getErr GetParametersError
+	if !errors.As(err, &getErr) {
+		t.Fatalf("got error %v, want GetParametersError", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (no retry for a non-retryable error)", attempts)
+	}
+}
+
+func TestBatchFetchExhaustsRetriesAndReturnsLastError(t *testing.T) {
+	attempts := 0
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, _ *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			attempts++
+			return nil, &smithy.GenericAPIError{Code: "ThrottlingException"}
+		},
+	}
+
+	_, err := batchFetch(context.Background(), cli, []string{"k"}, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	var getErr GetParametersError
+	if !errors.As(err, &getErr) {
+		t.Fatalf("got error %v, want GetParametersError", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(getErr.Cause, &apiErr) || apiErr.ErrorCode() != "ThrottlingException" {
+		t.Errorf("got cause %v, want the last ThrottlingException", getErr.Cause)
+	}
+}
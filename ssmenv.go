@@ -1,6 +1,8 @@
 // ssmenv provides a way to replace environment variables with AWS Systems Manager Parameter Store values.
 // If an environment variable value starts with "ssm://", it will be replaced with the value of the SSM parameter.
-// If no environment variable starts with "ssm://", the original environment variables are returned.
+// If an environment variable value starts with "ssm-path://", it is expanded into one environment variable per
+// parameter found recursively under that path. If neither prefix is present, the original environment variables are
+// returned.
 package ssmenv
 
 import (
@@ -9,7 +11,6 @@ import (
 	"log/slog"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
@@ -63,85 +64,97 @@ func (e NullParameterError) Error() string {
 }
 
 // ReplacedEnv replaces environment variable values with corresponding SSM parameter values. If the value of an
-// environment variable begins with "ssm://", it is replaced by the corresponding SSM parameter value.
+// environment variable begins with "ssm://", it is replaced by the corresponding SSM parameter value. A name may be
+// pinned to a specific version ("ssm://name:5") or label ("ssm://name@production"), matching AWS's conventions for
+// GetParameter. If it begins with "ssm-path://", the entry is dropped and replaced by one environment variable per
+// parameter found recursively under that path; see WithPathKeyMode for how the resulting names are derived.
 //
 // `cli` is the AWS SSM client used to retrieve the parameters. `envs` is a list of environment variables in the format
 // "KEY=VALUE", similar to what is returned by os.Environ().
 //
-// If no environment variable starts with "ssm://", no API calls are made, and the original environment variables are
-// returned unchanged.
+// If no environment variable starts with "ssm://" or "ssm-path://", no API calls are made, and the original
+// environment variables are returned unchanged.
 //
 // ReplacedEnv returns a map of environment variables, where values are replaced with SSM parameter values as needed.
 //
 // This function may return an error. Refer to the package's error definitions for details.
-func ReplacedEnv(ctx context.Context, cli ssmClient, envs []string) (map[string]string, error) {
+func ReplacedEnv(ctx context.Context, cli ssmClient, envs []string, opts ...Option) (map[string]string, error) {
+	orig, _, err := resolveEnv(ctx, cli, envs, nil, opts...)
+	return orig, err
+}
+
+// resolveEnv implements ReplacedEnv's substitution logic, additionally resolving any
+// extra SSM parameter names (e.g. FileTarget.Name values from ReplaceEnvAndFiles)
+// through the same batched GetParameters round-trip as the "ssm://" entries in envs.
+// It returns the replaced environment map and the raw name-to-value map used to
+// satisfy "ssm://" entries and extra, so callers can look up extra's values too.
+func resolveEnv(ctx context.Context, cli ssmClient, envs []string, extra []string, opts ...Option) (map[string]string, map[string]string, error) {
+	o := newOptions(opts)
+
 	orig := make(map[string]string)
-	ssmKeys := []string{}
+	ssmKeys := append([]string{}, extra...)
+	pathKeys := []string{}
 
 	for _, env := range envs {
 		pair := strings.SplitN(env, "=", 2)
 		if len(pair) != 2 {
-			return nil, InvalidEnvVarFormatError{OriginalEnvVar: env}
+			return nil, nil, InvalidEnvVarFormatError{OriginalEnvVar: env}
 		}
 		key := pair[0]
 		value := pair[1]
 		orig[key] = value
 
-		if strings.HasPrefix(value, ssmPrefix) {
+		switch {
+		case strings.HasPrefix(value, ssmPrefix):
 			ssmKeys = append(ssmKeys, strings.TrimPrefix(value, ssmPrefix))
+		case strings.HasPrefix(value, ssmPathPrefix):
+			pathKeys = append(pathKeys, key)
 		}
 	}
 
-	if len(ssmKeys) == 0 {
-		return orig, nil
+	var ps map[string]string
+	if len(ssmKeys) > 0 {
+		slog.InfoContext(ctx, "fetching SSM parameters", slog.String("keys", strings.Join(ssmKeys, ",")))
+		fetched, err := resolveNames(ctx, cli, ssmKeys, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		ps = fetched
 	}
 
-	slog.InfoContext(ctx, "fetching SSM parameters", slog.String("keys", strings.Join(ssmKeys, ",")))
-	ps, err := batchFetch(ctx, cli, ssmKeys)
-	if err != nil {
-		return nil, err
-	}
 	for k, v := range orig {
 		if strings.HasPrefix(v, ssmPrefix) {
-			// Remove prefix, use strings.TrimPrefix
 			key := strings.TrimPrefix(v, ssmPrefix)
 			val, ok := ps[key]
 			if !ok {
-				return nil, ParameterNotFoundError{Key: key}
+				return nil, nil, ParameterNotFoundError{Key: key}
 			}
 
 			orig[k] = val
 		}
 	}
 
-	return orig, nil
+	for _, key := range pathKeys {
+		path := strings.TrimPrefix(orig[key], ssmPathPrefix)
+		slog.InfoContext(ctx, "fetching SSM parameters by path", slog.String("path", path))
+		pp, err := fetchByPath(ctx, cli, path, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		delete(orig, key)
+		for name, val := range pp {
+			orig[envKeyForPathParam(o.pathKeyMode, key, path, name)] = val
+		}
+	}
+
+	return orig, ps, nil
 }
 
 const ssmPrefix = "ssm://"
 
 type ssmClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
-}
-
-func batchFetch(ctx context.Context, cli ssmClient, keys []string) (map[string]string, error) {
-	input := ssm.GetParametersInput{
-		Names:          keys,
-		WithDecryption: aws.Bool(true),
-	}
-	res, err := cli.GetParameters(ctx, &input)
-	if err != nil {
-		return nil, GetParametersError{Cause: err}
-	}
-	if len(res.InvalidParameters) > 0 {
-		return nil, InvalidParametersError{res.InvalidParameters}
-	}
-
-	ret := make(map[string]string)
-	for _, p := range res.Parameters {
-		if p.Name == nil || p.Value == nil {
-			return nil, NullParameterError{}
-		}
-		ret[*p.Name] = *p.Value
-	}
-	return ret, nil
+	GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
 }
@@ -0,0 +1,207 @@
+package ssmenv
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequiredFieldError is returned by Process when a field tagged `required:"true"` has
+// no value from "ssm", "env" or "default".
+type RequiredFieldError struct {
+	Field string
+}
+
+func (e RequiredFieldError) Error() string {
+	return fmt.Sprintf("required field has no value: %s", e.Field)
+}
+
+// UnsupportedFieldTypeError is returned by Process when a tagged field's type cannot
+// be populated from a string value.
+type UnsupportedFieldTypeError struct {
+	Type reflect.Type
+}
+
+func (e UnsupportedFieldTypeError) Error() string {
+	return fmt.Sprintf("unsupported field type: %s", e.Type)
+}
+
+// Unmarshal populates the exported fields of the struct pointed to by v, using "ssm",
+// "env", "default" and "required" struct tags, analogous to envconfig. A field tagged
+// `ssm:"/path/to/param"` is fetched from Parameter Store; `env:"NAME"` is read from
+// os.Getenv; `default:"..."` supplies a fallback value; `required:"true"` causes
+// Process to return a RequiredFieldError when no value was found.
+//
+// Unmarshal collects every "ssm" tagged parameter name up front and resolves them
+// through the same batched GetParameters pipeline used by ReplacedEnv, then assigns
+// fields via reflection.
+func Unmarshal(ctx context.Context, cli ssmClient, v interface{}, opts ...Option) error {
+	names := collectSSMNames(v)
+
+	var values map[string]string
+	if len(names) > 0 {
+		vs, err := resolveNames(ctx, cli, names, opts...)
+		if err != nil {
+			return err
+		}
+		values = vs
+	}
+
+	return Process(v, values)
+}
+
+// Process assigns "env"-, "default"- and "required"-tagged fields of v, looking up
+// "ssm"-tagged fields in ssmValues (as fetched by Unmarshal). Most callers should use
+// Unmarshal; Process is exposed for callers that resolve SSM parameters themselves.
+func Process(v interface{}, ssmValues map[string]string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ssmenv: Process requires a non-nil pointer to a struct, got %T", v)
+	}
+	return processStruct(rv.Elem(), ssmValues)
+}
+
+func processStruct(rv reflect.Value, ssmValues map[string]string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+
+		if fv.Kind() == reflect.Struct && !hasAnyTag(field) {
+			if err := processStruct(fv, ssmValues); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, found, err := resolveFieldValue(field, ssmValues)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if field.Tag.Get("required") == "true" {
+				return RequiredFieldError{Field: field.Name}
+			}
+			continue
+		}
+
+		if err := setField(fv, value); err != nil {
+			return fmt.Errorf("ssmenv: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func hasAnyTag(field reflect.StructField) bool {
+	_, ssmOk := field.Tag.Lookup("ssm")
+	_, envOk := field.Tag.Lookup("env")
+	_, defOk := field.Tag.Lookup("default")
+	return ssmOk || envOk || defOk
+}
+
+func resolveFieldValue(field reflect.StructField, ssmValues map[string]string) (string, bool, error) {
+	if name, ok := field.Tag.Lookup("ssm"); ok {
+		if val, ok := ssmValues[name]; ok {
+			return val, true, nil
+		}
+	}
+	if name, ok := field.Tag.Lookup("env"); ok {
+		if val, ok := os.LookupEnv(name); ok {
+			return val, true, nil
+		}
+	}
+	if def, ok := field.Tag.Lookup("default"); ok {
+		return def, true, nil
+	}
+	return "", false, nil
+}
+
+func collectSSMNames(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	var walk func(reflect.Value)
+	walk = func(rv reflect.Value) {
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if name, ok := field.Tag.Lookup("ssm"); ok {
+				names = append(names, name)
+				continue
+			}
+			fv := rv.Field(i)
+			if fv.Kind() == reflect.Struct {
+				walk(fv)
+			}
+		}
+	}
+	walk(rv.Elem())
+	return names
+}
+
+func setField(fv reflect.Value, value string) error {
+	if fv.CanAddr() {
+		if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch fv.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return UnsupportedFieldTypeError{Type: fv.Type()}
+		}
+		parts := strings.Split(value, ",")
+		for i, p := range parts {
+			parts[i] = strings.TrimSpace(p)
+		}
+		fv.Set(reflect.ValueOf(parts))
+	default:
+		return UnsupportedFieldTypeError{Type: fv.Type()}
+	}
+	return nil
+}
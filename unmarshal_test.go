@@ -0,0 +1,195 @@
+package ssmenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type exclaimedString string
+
+func (u *exclaimedString) UnmarshalText(text []byte) error {
+	*u = exclaimedString(string(text) + "!")
+	return nil
+}
+
+func TestProcessAssignsSupportedTypes(t *testing.T) {
+	var cfg struct {
+		Str      string          `default:"hello"`
+		Int      int             `default:"42"`
+		Float    float64         `default:"3.5"`
+		Bool     bool            `default:"true"`
+		Duration time.Duration   `default:"1500ms"`
+		List     []string        `default:"a, b ,c"`
+		Custom   exclaimedString `default:"shout"`
+	}
+
+	if err := Process(&cfg, nil); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+
+	if cfg.Str != "hello" {
+		t.Errorf("Str = %q, want %q", cfg.Str, "hello")
+	}
+	if cfg.Int != 42 {
+		t.Errorf("Int = %d, want %d", cfg.Int, 42)
+	}
+	if cfg.Float != 3.5 {
+		t.Errorf("Float = %v, want %v", cfg.Float, 3.5)
+	}
+	if !cfg.Bool {
+		t.Errorf("Bool = %v, want true", cfg.Bool)
+	}
+	if cfg.Duration != 1500*time.Millisecond {
+		t.Errorf("Duration = %v, want %v", cfg.Duration, 1500*time.Millisecond)
+	}
+	wantList := []string{"a", "b", "c"}
+	if len(cfg.List) != len(wantList) {
+		t.Fatalf("List = %v, want %v", cfg.List, wantList)
+	}
+	for i := range wantList {
+		if cfg.List[i] != wantList[i] {
+			t.Errorf("List = %v, want %v", cfg.List, wantList)
+		}
+	}
+	if cfg.Custom != "shout!" {
+		t.Errorf("Custom = %q, want %q", cfg.Custom, "shout!")
+	}
+}
+
+func TestProcessEnvOverridesDefault(t *testing.T) {
+	t.Setenv("SSMENV_TEST_NAME", "from-env")
+
+	var cfg struct {
+		Name string `env:"SSMENV_TEST_NAME" default:"from-default"`
+	}
+	if err := Process(&cfg, nil); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if cfg.Name != "from-env" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "from-env")
+	}
+}
+
+func TestProcessFallsBackToDefaultWhenEnvUnset(t *testing.T) {
+	os.Unsetenv("SSMENV_TEST_UNSET")
+
+	var cfg struct {
+		Name string `env:"SSMENV_TEST_UNSET" default:"from-default"`
+	}
+	if err := Process(&cfg, nil); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if cfg.Name != "from-default" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "from-default")
+	}
+}
+
+func TestProcessRequiredFieldMissing(t *testing.T) {
+	var cfg struct {
+		Name string `env:"SSMENV_TEST_MISSING_REQUIRED" required:"true"`
+	}
+	os.Unsetenv("SSMENV_TEST_MISSING_REQUIRED")
+
+	err := Process(&cfg, nil)
+	var reqErr RequiredFieldError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("got error %v, want RequiredFieldError", err)
+	}
+	if reqErr.Field != "Name" {
+		t.Errorf("Field = %q, want %q", reqErr.Field, "Name")
+	}
+}
+
+func TestProcessSSMFieldFromValues(t *testing.T) {
+	var cfg struct {
+		Host string `ssm:"/app/host"`
+	}
+	if err := Process(&cfg, map[string]string{"/app/host": "db.internal"}); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if cfg.Host != "db.internal" {
+		t.Errorf("Host = %q, want %q", cfg.Host, "db.internal")
+	}
+}
+
+func TestProcessSSMFieldMissingNotRequired(t *testing.T) {
+	var cfg struct {
+		Host string `ssm:"/app/host"`
+	}
+	if err := Process(&cfg, nil); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if cfg.Host != "" {
+		t.Errorf("Host = %q, want empty", cfg.Host)
+	}
+}
+
+func TestProcessNestedStructRecurses(t *testing.T) {
+	type inner struct {
+		Name string `default:"nested-default"`
+	}
+	var cfg struct {
+		Inner inner
+	}
+	if err := Process(&cfg, nil); err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if cfg.Inner.Name != "nested-default" {
+		t.Errorf("Inner.Name = %q, want %q", cfg.Inner.Name, "nested-default")
+	}
+}
+
+func TestProcessUnsupportedFieldType(t *testing.T) {
+	var cfg struct {
+		Bad map[string]string `default:"x"`
+	}
+	err := Process(&cfg, nil)
+	var typeErr UnsupportedFieldTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("got error %v, want UnsupportedFieldTypeError", err)
+	}
+}
+
+func TestCollectSSMNamesWalksNestedStructs(t *testing.T) {
+	type inner struct {
+		Port string `ssm:"/app/port"`
+	}
+	var cfg struct {
+		Host  string `ssm:"/app/host"`
+		Inner inner
+	}
+
+	names := collectSSMNames(&cfg)
+	if len(names) != 2 {
+		t.Fatalf("got names %v, want 2 entries", names)
+	}
+}
+
+func TestUnmarshalFetchesSSMValuesThroughBatchPipeline(t *testing.T) {
+	cli := &fakeSSMClient{
+		getParameters: func(_ context.Context, in *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+			var ps []ssmtypes.Parameter
+			for _, n := range in.Names {
+				ps = append(ps, ssmtypes.Parameter{Name: aws.String(n), Value: aws.String("resolved-" + n)})
+			}
+			return &ssm.GetParametersOutput{Parameters: ps}, nil
+		},
+	}
+
+	var cfg struct {
+		Host string `ssm:"/app/host"`
+	}
+	if err := Unmarshal(context.Background(), cli, &cfg); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if cfg.Host != "resolved-/app/host" {
+		t.Errorf("Host = %q, want %q", cfg.Host, "resolved-/app/host")
+	}
+}